@@ -0,0 +1,223 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// completionCmd emits a shell completion script, or installs one into the
+// caller's rc file, and answers bash/zsh TAB requests for alias/bucket/object
+// paths against the configured hosts.
+var completionCmd = cli.Command{
+	Name:   "completion",
+	Usage:  "Generate shell completion scripts",
+	Action: mainCompletion,
+	Flags:  []cli.Flag{completionShellFlag},
+	Subcommands: []cli.Command{
+		completionInstallCmd,
+	},
+	CustomHelpTemplate: `NAME:
+   mc {{.Name}} - {{.Usage}}
+
+USAGE:
+   mc {{.Name}} [--shell=bash|zsh|fish]
+
+   mc {{.Name}} install
+
+EXAMPLES:
+   1. Print a bash completion script to stdout.
+      $ mc completion --shell=bash
+
+   2. Install completions for the running shell into the user rc file.
+      $ mc completion install
+`,
+}
+
+var completionShellFlag = cli.StringFlag{
+	Name:  "shell",
+	Value: "bash",
+	Usage: "Shell to generate completions for: bash, zsh, fish",
+}
+
+var completionInstallCmd = cli.Command{
+	Name:   "install",
+	Usage:  "Install completions into the current user's shell rc file",
+	Action: mainCompletionInstall,
+}
+
+// isCompletionMode reports whether cli is asking us to generate completions
+// rather than run a command for real, so registerBefore can skip migrate()
+// and checkConfig() and keep TAB latency well under 100ms. cli appends
+// completionModeArg as the last argument of the invocation it re-execs for
+// completions (e.g. "mc cp s3/bucket/ --generate-bash-completion"), so this
+// has to scan the whole slice rather than just the first argument.
+func isCompletionMode(args []string) bool {
+	for _, arg := range args {
+		if arg == completionModeArg {
+			return true
+		}
+	}
+	return false
+}
+
+// completionModeArg is the hidden argument cli passes every command when it
+// is generating completions, so we can short-circuit checkConfig()/migrate().
+const completionModeArg = "--generate-bash-completion"
+
+func mainCompletion(ctx *cli.Context) {
+	shell := ctx.String("shell")
+	script, e := completionScript(shell)
+	fatalIf(probe.NewError(e), "Unable to generate "+shell+" completion script.")
+	fmt.Print(script)
+}
+
+func mainCompletionInstall(ctx *cli.Context) {
+	shell := os.Getenv("SHELL")
+	rcFile, e := completionRCFile(shell)
+	fatalIf(probe.NewError(e), "Unable to determine shell rc file.")
+
+	script, e := completionScript(filepath.Base(shell))
+	fatalIf(probe.NewError(e), "Unable to generate completion script.")
+
+	f, e := os.OpenFile(rcFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	fatalIf(probe.NewError(e), "Unable to open "+rcFile+" for writing.")
+	defer f.Close()
+
+	if _, e = f.WriteString("\n# mc shell completion\n" + script); e != nil {
+		fatalIf(probe.NewError(e), "Unable to write completion script to "+rcFile+".")
+	}
+	console.Infoln("Installed completions into " + rcFile + ", restart your shell to use them.")
+}
+
+// completionRCFile resolves the rc file mc should append its completion
+// snippet to, based on $SHELL and the current user's home directory.
+func completionRCFile(shell string) (string, error) {
+	u, e := user.Current()
+	if e != nil {
+		return "", e
+	}
+	switch filepath.Base(shell) {
+	case "zsh":
+		return filepath.Join(u.HomeDir, ".zshrc"), nil
+	case "fish":
+		return filepath.Join(u.HomeDir, ".config", "fish", "completions", "mc.fish"), nil
+	default:
+		return filepath.Join(u.HomeDir, ".bashrc"), nil
+	}
+}
+
+func completionScript(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashCompletionScript, nil
+	case "zsh":
+		return zshCompletionScript, nil
+	case "fish":
+		return fishCompletionScript, nil
+	default:
+		return "", fmt.Errorf("unsupported shell ‘%s’, expected one of bash, zsh, fish", shell)
+	}
+}
+
+// completeObjects answers TAB completion for a partial alias/bucket/object
+// path by listing the matching prefix against the configured alias. It is
+// wired up as the per-command cli.Command.BashComplete hook so only commands
+// that take URLs (cp, mirror, ls, cat, ...) pay the lookup cost.
+func completeObjects(ctx *cli.Context) {
+	last := lastArg(os.Args)
+	if last == "" {
+		return
+	}
+
+	config, err := getMcConfig()
+	if err != nil {
+		// Completion must never fail loudly; just offer nothing.
+		return
+	}
+
+	alias, urlStr, hasPrefix := splitCompletionArg(last, config.Hosts)
+	if !hasPrefix {
+		return
+	}
+
+	clnt, err := url2Client(urlStr)
+	if err != nil {
+		return
+	}
+
+	for content := range clnt.List(false, false) {
+		if content.Err != nil {
+			continue
+		}
+		fmt.Println(alias + content.URL.Path)
+	}
+}
+
+// init wires completeObjects up as the BashComplete hook for every command
+// that takes an alias/bucket/object path, so `mc cp s3/mybucket/<TAB>`
+// actually resolves against the configured hosts instead of falling back to
+// the shell's default filename completion.
+func init() {
+	for _, cmd := range []*cli.Command{&lsCmd, &catCmd, &cpCmd, &mirrorCmd, &diffCmd} {
+		cmd.BashComplete = completeObjects
+	}
+}
+
+// splitCompletionArg figures out which configured alias, if any, prefixes
+// the partial argument the shell is asking us to complete.
+func splitCompletionArg(arg string, hosts map[string]hostConfig) (alias, urlStr string, ok bool) {
+	for name := range hosts {
+		prefix := name + "/"
+		if strings.HasPrefix(arg, prefix) {
+			return prefix, arg, true
+		}
+	}
+	return "", "", false
+}
+
+func lastArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[len(args)-1]
+}
+
+const bashCompletionScript = `_mc_completion() {
+    local cur
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    COMPREPLY=($(compgen -W "$(mc "${COMP_WORDS[@]:1:COMP_CWORD-1}" --generate-bash-completion)" -- "$cur"))
+    return 0
+}
+complete -o default -F _mc_completion mc
+`
+
+const zshCompletionScript = `autoload -U +X compinit && compinit
+autoload -U +X bashcompinit && bashcompinit
+` + bashCompletionScript
+
+const fishCompletionScript = `complete -c mc -a "(mc --generate-bash-completion)"
+`
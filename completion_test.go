@@ -0,0 +1,53 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "testing"
+
+func TestIsCompletionMode(t *testing.T) {
+	cases := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{}, false},
+		{[]string{"completion"}, false},
+		{[]string{"--generate-bash-completion"}, true},
+		{[]string{"cp", "s3/mybucket/", "--generate-bash-completion"}, true},
+	}
+	for _, c := range cases {
+		if got := isCompletionMode(c.args); got != c.want {
+			t.Errorf("isCompletionMode(%v) = %v, want %v", c.args, got, c.want)
+		}
+	}
+}
+
+func TestSplitCompletionArg(t *testing.T) {
+	hosts := map[string]hostConfig{
+		"s3":    {},
+		"local": {},
+	}
+
+	alias, urlStr, ok := splitCompletionArg("s3/mybucket/pre", hosts)
+	if !ok || alias != "s3/" || urlStr != "s3/mybucket/pre" {
+		t.Errorf("splitCompletionArg(s3/mybucket/pre) = (%q, %q, %v), want (s3/, s3/mybucket/pre, true)", alias, urlStr, ok)
+	}
+
+	_, _, ok = splitCompletionArg("unknownalias/bucket", hosts)
+	if ok {
+		t.Error("expected no match for an alias that isn't configured")
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// cpCmd copies objects and files from multiple sources to a single
+// destination, routing every object through doTransfer so --log-file and
+// --metrics-listen observe it the same way mirror and session do.
+var cpCmd = cli.Command{
+	Name:   "cp",
+	Usage:  "Copy one or more objects to a target",
+	Action: mainCopy,
+}
+
+func mainCopy(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fatalIf(probe.NewError(errors.New("")), "Usage: mc cp SOURCE [SOURCE...] TARGET")
+	}
+
+	dst := args[len(args)-1]
+	alias := aliasOf(dst)
+	for _, src := range args[:len(args)-1] {
+		if err := doTransfer("cp", alias, src, dst); err != nil {
+			fatalIf(err.Trace(src, dst), "Unable to copy "+src+" to "+dst+".")
+		}
+	}
+}
+
+// aliasOf returns the first path segment of urlStr, which is the alias of an
+// "alias/bucket/object"-style mc URL. The segment is not checked against the
+// configured aliases, so a relative local path such as "dir/file" yields
+// "dir"; a path with no "/" or starting with "/" yields "".
+func aliasOf(urlStr string) string {
+	i := strings.Index(urlStr, "/")
+	if i < 0 {
+		return ""
+	}
+	return urlStr[:i]
+}
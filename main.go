@@ -65,9 +65,11 @@ func getSystemData() map[string]string {
 	platform := fmt.Sprintf("Host: %s | OS: %s | Arch: %s", host, runtime.GOOS, runtime.GOARCH)
 	goruntime := fmt.Sprintf("Version: %s | CPUs: %s", runtime.Version(), strconv.Itoa(runtime.NumCPU()))
 	return map[string]string{
-		"PLATFORM": platform,
-		"RUNTIME":  goruntime,
-		"MEM":      mem,
+		"PLATFORM":   platform,
+		"RUNTIME":    goruntime,
+		"MEM":        mem,
+		"USER-AGENT": globalUserAgent,
+		"REQUEST-ID": globalRequestID,
 	}
 }
 
@@ -81,12 +83,32 @@ func registerBefore(ctx *cli.Context) error {
 		console.NoDebugPrint = false
 	}
 
+	// Shells re-invoke mc on every keystroke to compute completions, so skip
+	// runtime verification, migration and config sanity checks here to keep
+	// TAB latency well under 100ms.
+	if isCompletionMode(os.Args[1:]) {
+		return nil
+	}
+
 	verifyMCRuntime()
 
 	// Migrate any old version of config / state files to newer format.
 	migrate()
 
 	checkConfig()
+
+	if logFile := ctx.GlobalString("log-file"); logFile != "" {
+		sink, err := newEventSink(logFile, ctx.GlobalString("log-format"))
+		fatalIf(err.Trace(logFile), "Unable to open log file.")
+		globalEventSink = sink
+	}
+
+	globalUserAgent = makeUserAgent(ctx.GlobalString("user-agent-extra"))
+	globalRequestID = newRequestID()
+
+	if addr := ctx.GlobalString("metrics-listen"); addr != "" {
+		startMetricsServer(addr)
+	}
 	return nil
 }
 
@@ -101,25 +123,30 @@ func getFormattedVersion() string {
 
 func registerApp() *cli.App {
 	// Register all the commands
-	registerCmd(lsCmd)      // List contents of a bucket
-	registerCmd(mbCmd)      // make a bucket
-	registerCmd(catCmd)     // concantenate an object to standard output
-	registerCmd(cpCmd)      // copy objects and files from multiple sources to single destination
-	registerCmd(mirrorCmd)  // mirror objects and files from single source to multiple destinations
-	registerCmd(sessionCmd) // session handling for resuming copy and mirror operations
-	registerCmd(shareCmd)   // share any given url for third party access
-	registerCmd(diffCmd)    // compare two objects
-	registerCmd(accessCmd)  // set permissions [public, private, readonly, authenticated] for buckets and folders.
-	registerCmd(configCmd)  // generate configuration "/home/harsha/.mc/config.json" file.
-	registerCmd(updateCmd)  // update Check for new software updates
-	registerCmd(versionCmd) // print version
+	registerCmd(lsCmd)         // List contents of a bucket
+	registerCmd(mbCmd)         // make a bucket
+	registerCmd(catCmd)        // concantenate an object to standard output
+	registerCmd(cpCmd)         // copy objects and files from multiple sources to single destination
+	registerCmd(mirrorCmd)     // mirror objects and files from single source to single destination
+	registerCmd(sessionCmd)    // session handling for resuming copy and mirror operations
+	registerCmd(shareCmd)      // share any given url for third party access
+	registerCmd(diffCmd)       // compare two objects
+	registerCmd(accessCmd)     // set permissions [public, private, readonly, authenticated] for buckets and folders.
+	registerCmd(configCmd)     // generate configuration "/home/harsha/.mc/config.json" file.
+	registerCmd(updateCmd)     // update Check for new software updates
+	registerCmd(versionCmd)    // print version
+	registerCmd(completionCmd) // generate/install shell completion scripts
 
 	// register all the flags
-	registerFlag(configFlag) // path to config folder
-	registerFlag(quietFlag)  // suppress console output
-	registerFlag(mimicFlag)  // OS toolchain mimic
-	registerFlag(jsonFlag)   // json formatted output
-	registerFlag(debugFlag)  // enable debugging output
+	registerFlag(configFlag)         // path to config folder
+	registerFlag(quietFlag)          // suppress console output
+	registerFlag(mimicFlag)          // OS toolchain mimic
+	registerFlag(jsonFlag)           // json formatted output
+	registerFlag(debugFlag)          // enable debugging output
+	registerFlag(logFileFlag)        // structured event log destination
+	registerFlag(logFormatFlag)      // structured event log format
+	registerFlag(userAgentExtraFlag) // extra User-Agent token for request correlation
+	registerFlag(metricsListenFlag)  // Prometheus /metrics exporter address
 
 	app := cli.NewApp()
 	app.Usage = "Minio Client for cloud storage and filesystems"
@@ -167,4 +194,8 @@ func main() {
 	}
 
 	app.RunAndExitOnError()
+
+	if globalEventSink != nil {
+		globalEventSink.Close()
+	}
 }
@@ -0,0 +1,174 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/console"
+)
+
+// metricsListenFlag starts a Prometheus text-format exporter on the given
+// address while cp/mirror/diff run, so a long-running `mc mirror` can be
+// scraped the same way operators already scrape the MinIO server.
+var metricsListenFlag = cli.StringFlag{
+	Name:  "metrics-listen",
+	Usage: "Serve Prometheus metrics on ADDR (e.g. :9000) while the command runs",
+}
+
+// metricsCollector holds the counters/gauges published on /metrics. Counter
+// values and the gauges are updated atomically and the counter maps are
+// guarded by mutex, since cp/mirror drive them from multiple worker
+// goroutines.
+type metricsCollector struct {
+	objectsCopied map[string]*int64 // keyed by "op,alias"
+	bytesCopied   map[string]*int64
+	errors        map[string]*int64
+	inflight      int64
+	queueDepth    int64
+	mutex         sync.Mutex
+}
+
+// globalMetrics is non-nil only when --metrics-listen was passed.
+var globalMetrics *metricsCollector
+
+func newMetricsCollector() *metricsCollector {
+	return &metricsCollector{
+		objectsCopied: map[string]*int64{},
+		bytesCopied:   map[string]*int64{},
+		errors:        map[string]*int64{},
+	}
+}
+
+func (m *metricsCollector) counter(set map[string]*int64, op, alias string) *int64 {
+	key := op + "," + alias
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	c, ok := set[key]
+	if !ok {
+		var zero int64
+		c = &zero
+		set[key] = c
+	}
+	return c
+}
+
+// ObjectCopied records one successfully transferred object for op/alias.
+func (m *metricsCollector) ObjectCopied(op, alias string, bytes int64) {
+	atomic.AddInt64(m.counter(m.objectsCopied, op, alias), 1)
+	atomic.AddInt64(m.counter(m.bytesCopied, op, alias), bytes)
+}
+
+// ObjectFailed records one failed transfer for op/alias.
+func (m *metricsCollector) ObjectFailed(op, alias string) {
+	atomic.AddInt64(m.counter(m.errors, op, alias), 1)
+}
+
+// SetInflight reports the current number of in-progress transfers.
+func (m *metricsCollector) SetInflight(n int64) {
+	atomic.StoreInt64(&m.inflight, n)
+}
+
+// SetQueueDepth reports the current size of the pending transfer queue.
+func (m *metricsCollector) SetQueueDepth(n int64) {
+	atomic.StoreInt64(&m.queueDepth, n)
+}
+
+// ServeHTTP renders every counter/gauge in Prometheus text exposition
+// format, plus go_memstats_* gauges read through runtime.ReadMemStats the
+// same way getSystemData() does for --debug.
+func (m *metricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "# HELP mc_objects_copied_total Objects successfully transferred")
+	fmt.Fprintln(w, "# TYPE mc_objects_copied_total counter")
+	m.writeLabeled(w, "mc_objects_copied_total", m.objectsCopied)
+
+	fmt.Fprintln(w, "# HELP mc_bytes_copied_total Bytes successfully transferred")
+	fmt.Fprintln(w, "# TYPE mc_bytes_copied_total counter")
+	m.writeLabeled(w, "mc_bytes_copied_total", m.bytesCopied)
+
+	fmt.Fprintln(w, "# HELP mc_errors_total Transfer errors")
+	fmt.Fprintln(w, "# TYPE mc_errors_total counter")
+	m.writeLabeled(w, "mc_errors_total", m.errors)
+
+	fmt.Fprintln(w, "# HELP mc_inflight_transfers Transfers currently in progress")
+	fmt.Fprintln(w, "# TYPE mc_inflight_transfers gauge")
+	fmt.Fprintf(w, "mc_inflight_transfers %d\n", atomic.LoadInt64(&m.inflight))
+
+	fmt.Fprintln(w, "# HELP mc_queue_depth Transfers queued but not yet started")
+	fmt.Fprintln(w, "# TYPE mc_queue_depth gauge")
+	fmt.Fprintf(w, "mc_queue_depth %d\n", atomic.LoadInt64(&m.queueDepth))
+
+	writeMemStatsMetrics(w)
+}
+
+func (m *metricsCollector) writeLabeled(w http.ResponseWriter, name string, set map[string]*int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for key, v := range set {
+		op, alias := splitMetricKey(key)
+		fmt.Fprintf(w, "%s{op=%q,alias=%q} %d\n", name, op, alias, atomic.LoadInt64(v))
+	}
+}
+
+func splitMetricKey(key string) (op, alias string) {
+	for i := 0; i < len(key); i++ {
+		if key[i] == ',' {
+			return key[:i], key[i+1:]
+		}
+	}
+	return key, ""
+}
+
+// writeMemStatsMetrics publishes go_memstats_* gauges from a fresh
+// runtime.MemStats snapshot, the same collection getSystemData() makes for
+// --debug output.
+func writeMemStatsMetrics(w http.ResponseWriter) {
+	memstats := &runtime.MemStats{}
+	runtime.ReadMemStats(memstats)
+
+	fmt.Fprintln(w, "# HELP go_memstats_alloc_bytes Bytes currently allocated")
+	fmt.Fprintln(w, "# TYPE go_memstats_alloc_bytes gauge")
+	fmt.Fprintf(w, "go_memstats_alloc_bytes %d\n", memstats.Alloc)
+
+	fmt.Fprintln(w, "# HELP go_memstats_heap_alloc_bytes Heap bytes currently allocated")
+	fmt.Fprintln(w, "# TYPE go_memstats_heap_alloc_bytes gauge")
+	fmt.Fprintf(w, "go_memstats_heap_alloc_bytes %d\n", memstats.HeapAlloc)
+
+	fmt.Fprintln(w, "# HELP go_memstats_sys_bytes Bytes obtained from the OS")
+	fmt.Fprintln(w, "# TYPE go_memstats_sys_bytes gauge")
+	fmt.Fprintf(w, "go_memstats_sys_bytes %d\n", memstats.Sys)
+}
+
+// startMetricsServer starts the /metrics exporter in the background and
+// returns immediately; it is torn down implicitly when the process exits.
+func startMetricsServer(addr string) {
+	globalMetrics = newMetricsCollector()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", globalMetrics)
+
+	go func() {
+		if e := http.ListenAndServe(addr, mux); e != nil {
+			console.Errorln("Unable to serve metrics on " + addr + ": " + e.Error())
+		}
+	}()
+}
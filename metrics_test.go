@@ -0,0 +1,48 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "testing"
+
+func TestSplitMetricKey(t *testing.T) {
+	op, alias := splitMetricKey("cp,s3")
+	if op != "cp" || alias != "s3" {
+		t.Errorf("splitMetricKey(cp,s3) = (%q, %q), want (cp, s3)", op, alias)
+	}
+
+	op, alias = splitMetricKey("mirror")
+	if op != "mirror" || alias != "" {
+		t.Errorf("splitMetricKey(mirror) = (%q, %q), want (mirror, \"\")", op, alias)
+	}
+}
+
+func TestMetricsCollectorRecordsActivity(t *testing.T) {
+	m := newMetricsCollector()
+	m.ObjectCopied("cp", "s3", 100)
+	m.ObjectCopied("cp", "s3", 50)
+	m.ObjectFailed("cp", "s3")
+
+	if got := *m.counter(m.objectsCopied, "cp", "s3"); got != 2 {
+		t.Errorf("objectsCopied = %d, want 2", got)
+	}
+	if got := *m.counter(m.bytesCopied, "cp", "s3"); got != 150 {
+		t.Errorf("bytesCopied = %d, want 150", got)
+	}
+	if got := *m.counter(m.errors, "cp", "s3"); got != 1 {
+		t.Errorf("errors = %d, want 1", got)
+	}
+}
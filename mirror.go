@@ -0,0 +1,75 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// mirrorCmd mirrors objects and files from a single source to a single
+// destination in one pass over the source; it has no --watch mode. Every
+// object it moves goes through doTransfer, the same entry point cp and
+// session use.
+var mirrorCmd = cli.Command{
+	Name:   "mirror",
+	Usage:  "Mirror a source to a target, keeping the target in sync",
+	Action: mainMirror,
+}
+
+func mainMirror(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fatalIf(probe.NewError(errors.New("")), "Usage: mc mirror SOURCE TARGET")
+	}
+	src, dst := args[0], args[1]
+	alias := aliasOf(dst)
+
+	srcClnt, err := url2Client(src)
+	fatalIf(err.Trace(src), "Unable to initialize source "+src+".")
+
+	var queued []string
+	for c := range srcClnt.List(true, false) {
+		if c.Err != nil {
+			continue
+		}
+		queued = append(queued, c.URL.Path)
+	}
+
+	if globalMetrics != nil {
+		globalMetrics.SetQueueDepth(int64(len(queued)))
+	}
+
+	for i, objectPath := range queued {
+		if globalMetrics != nil {
+			globalMetrics.SetInflight(1)
+			globalMetrics.SetQueueDepth(int64(len(queued) - i - 1))
+		}
+		if err := doTransfer("mirror", alias, src+objectPath, dst+objectPath); err != nil {
+			if globalMetrics != nil {
+				globalMetrics.SetInflight(0)
+			}
+			fatalIf(err.Trace(src+objectPath, dst+objectPath), "Unable to mirror "+src+objectPath+" to "+dst+objectPath+".")
+		}
+	}
+
+	if globalMetrics != nil {
+		globalMetrics.SetInflight(0)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"time"
+
+	"github.com/minio/minio/pkg/probe"
+)
+
+// doTransfer copies a single object from src to dst and is the one place
+// cp, mirror and session route every object through, so the event log and
+// the Prometheus counters both observe the same stream of transfers
+// regardless of which command drove them. It reports failure back to the
+// caller instead of swallowing it, so a command whose client backend can't
+// complete a transfer stops and fails loudly rather than looking like it
+// succeeded.
+func doTransfer(op, alias, src, dst string) *probe.Error {
+	start := time.Now()
+
+	srcClnt, err := url2Client(src)
+	if err != nil {
+		recordTransfer(op, alias, src, dst, 0, start, err.ToGoError())
+		return err
+	}
+	dstClnt, err := url2Client(dst)
+	if err != nil {
+		recordTransfer(op, alias, src, dst, 0, start, err.ToGoError())
+		return err
+	}
+
+	reader, size, err := srcClnt.Get()
+	if err != nil {
+		recordTransfer(op, alias, src, dst, 0, start, err.ToGoError())
+		return err
+	}
+	defer reader.Close()
+
+	if err = dstClnt.Put(reader, size); err != nil {
+		recordTransfer(op, alias, src, dst, size, start, err.ToGoError())
+		return err
+	}
+
+	recordTransfer(op, alias, src, dst, size, start, nil)
+	return nil
+}
+
+// recordTransfer funnels one completed transfer through the event log and
+// the Prometheus counters, so cp, mirror and session don't each need their
+// own --log-file/--metrics-listen plumbing.
+func recordTransfer(op, alias, src, dst string, size int64, start time.Time, transferErr error) {
+	logTransfer(op, src, dst, size, time.Since(start), transferErr)
+
+	if globalMetrics == nil {
+		return
+	}
+	if transferErr != nil {
+		globalMetrics.ObjectFailed(op, alias)
+		return
+	}
+	globalMetrics.ObjectCopied(op, alias, size)
+}
@@ -0,0 +1,370 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio/pkg/probe"
+)
+
+// updateCmd checks for, verifies and applies new mc releases, modeled on the
+// signed-manifest flow the server uses in its own update handler.
+var updateCmd = cli.Command{
+	Name:   "update",
+	Usage:  "Check for a new mc release and update in place",
+	Action: mainUpdate,
+	Flags: []cli.Flag{
+		updateManifestFlag,
+		updateChannelFlag,
+		updateDryRunFlag,
+		updateCheckOnlyFlag,
+		updateRollbackFlag,
+	},
+}
+
+var (
+	updateManifestFlag = cli.StringFlag{
+		Name:  "manifest",
+		Value: "https://dl.minio.io/client/mc/release/manifest.json",
+		Usage: "URL of the signed release manifest",
+	}
+	updateChannelFlag = cli.StringFlag{
+		Name:  "channel",
+		Value: "stable",
+		Usage: "Release channel to check: stable, rc",
+	}
+	updateDryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Report the update that would be applied without downloading it",
+	}
+	updateCheckOnlyFlag = cli.BoolFlag{
+		Name:  "check-only",
+		Usage: "Only report whether a new version is available",
+	}
+	updateRollbackFlag = cli.BoolFlag{
+		Name:  "rollback",
+		Usage: "Restore the previous binary saved as <path>.bak",
+	}
+)
+
+// releaseManifest is the signed JSON document published alongside each
+// release tarball. The signature covers every field below except itself,
+// including Digest, so a compromised download host cannot substitute a
+// binary without also forging a valid signature over its hash.
+type releaseManifest struct {
+	Tag         string            `json:"tag"`    // RFC3339 timestamp, same convention as Version
+	Channel     string            `json:"channel"`
+	DownloadURL map[string]string `json:"downloadURL"` // "linux-amd64" -> url, ...
+	Digest      map[string]string `json:"digest"`       // "linux-amd64" -> hex sha256 of the binary at DownloadURL
+	Signature   string            `json:"signature"`    // base64 detached ed25519 signature over the manifest with Signature cleared
+}
+
+// updateStatus is the madmin-style status struct emitted as JSON when
+// --json is passed, mirroring ServerUpdateV2Handler's response shape.
+type updateStatus struct {
+	CurrentVersion string `json:"currentVersion"`
+	UpdatedVersion string `json:"updatedVersion,omitempty"`
+	RolledBack     bool   `json:"rolledBack,omitempty"`
+	Err            string `json:"err,omitempty"`
+}
+
+// updatePubKey is the ed25519 public key pinned at build time that every
+// release manifest signature is verified against. Populated via -ldflags
+// the same way Version is.
+var updatePubKey string
+
+// packageManagerPaths are locations mc refuses to self-update under,
+// because the binary there is owned by the OS package manager.
+var packageManagerPaths = []string{
+	"/usr/bin",
+	"/usr/local/bin/homebrew",
+	"/opt/homebrew",
+}
+
+func mainUpdate(ctx *cli.Context) {
+	status := updateStatus{CurrentVersion: Version}
+
+	if ctx.Bool("rollback") {
+		err := rollbackUpdate()
+		if err != nil {
+			status.Err = err.ToGoError().Error()
+		} else {
+			status.RolledBack = true
+		}
+		printUpdateStatus(ctx, status, err)
+		return
+	}
+
+	channel := ctx.String("channel")
+	manifest, err := fetchReleaseManifest(manifestURLForChannel(ctx.String("manifest"), channel), channel)
+	fatalIf(err.Trace(ctx.String("manifest")), "Unable to fetch release manifest.")
+
+	newer, err := isNewerRelease(manifest.Tag)
+	fatalIf(err.Trace(manifest.Tag), "Unable to parse release manifest tag.")
+
+	if !newer {
+		status.UpdatedVersion = Version
+		printUpdateStatus(ctx, status, nil)
+		return
+	}
+
+	status.UpdatedVersion = manifest.Tag
+	if ctx.Bool("check-only") || ctx.Bool("dry-run") {
+		printUpdateStatus(ctx, status, nil)
+		return
+	}
+
+	err = applyUpdate(manifest)
+	if err != nil {
+		status.Err = err.ToGoError().Error()
+	}
+	printUpdateStatus(ctx, status, err)
+}
+
+func printUpdateStatus(ctx *cli.Context, status updateStatus, err *probe.Error) {
+	if ctx.GlobalBool("json") {
+		data, _ := json.Marshal(status)
+		fmt.Println(string(data))
+		return
+	}
+	fatalIf(err, "Unable to update mc.")
+	if status.RolledBack {
+		fmt.Println("Restored the previous mc binary.")
+		return
+	}
+	if status.UpdatedVersion == status.CurrentVersion {
+		fmt.Println("You are already running the latest version of mc.")
+		return
+	}
+	fmt.Println("Updated mc from", status.CurrentVersion, "to", status.UpdatedVersion)
+}
+
+// manifestURLForChannel parameterizes the configured manifest URL by
+// channel, e.g. ".../manifest.json" becomes ".../manifest-rc.json" for
+// --channel=rc, so --channel actually selects which manifest is fetched
+// instead of only being checked after the fact against the stable one.
+func manifestURLForChannel(base, channel string) string {
+	if channel == "" || channel == "stable" {
+		return base
+	}
+	return strings.TrimSuffix(base, ".json") + "-" + channel + ".json"
+}
+
+// fetchReleaseManifest downloads and JSON-decodes the manifest for the
+// requested channel.
+func fetchReleaseManifest(manifestURL, channel string) (releaseManifest, *probe.Error) {
+	parsed, e := url.Parse(manifestURL)
+	if e != nil {
+		return releaseManifest{}, probe.NewError(e)
+	}
+	if parsed.Scheme != "https" {
+		return releaseManifest{}, probe.NewError(fmt.Errorf("refusing to fetch release manifest over %q, only https is trusted", parsed.Scheme))
+	}
+
+	resp, e := newHTTPClient().Get(manifestURL)
+	if e != nil {
+		return releaseManifest{}, probe.NewError(e)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return releaseManifest{}, probe.NewError(fmt.Errorf("unexpected manifest status: %s", resp.Status))
+	}
+
+	var manifest releaseManifest
+	if e = json.NewDecoder(resp.Body).Decode(&manifest); e != nil {
+		return releaseManifest{}, probe.NewError(e)
+	}
+	if manifest.Channel != channel {
+		return releaseManifest{}, probe.NewError(fmt.Errorf("manifest channel %q does not match requested channel %q", manifest.Channel, channel))
+	}
+	if err := verifyManifestSignature(manifest); err != nil {
+		return releaseManifest{}, err
+	}
+	return manifest, nil
+}
+
+// verifyManifestSignature checks the manifest's detached ed25519 signature
+// against the public key pinned at build time.
+func verifyManifestSignature(manifest releaseManifest) *probe.Error {
+	pubKey, e := base64.StdEncoding.DecodeString(updatePubKey)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	sig, e := base64.StdEncoding.DecodeString(manifest.Signature)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	signed := manifest
+	signed.Signature = ""
+	payload, e := json.Marshal(signed)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(pubKey), payload, sig) {
+		return probe.NewError(errors.New("release manifest signature verification failed"))
+	}
+	return nil
+}
+
+// isNewerRelease compares a manifest's RFC3339 tag against the running
+// binary's Version using the same timestamp parser getFormattedVersion uses.
+func isNewerRelease(tag string) (bool, *probe.Error) {
+	current, e := time.Parse(time.RFC3339Nano, Version)
+	if e != nil {
+		return false, probe.NewError(e)
+	}
+	next, e := time.Parse(time.RFC3339Nano, tag)
+	if e != nil {
+		return false, probe.NewError(e)
+	}
+	return next.After(current), nil
+}
+
+// applyUpdate downloads the release binary for this OS/arch, verifies it
+// landed fully, saves the running binary as a rollback copy, and atomically
+// swaps the new one into place.
+func applyUpdate(manifest releaseManifest) *probe.Error {
+	self, e := os.Executable()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	self, e = filepath.EvalSymlinks(self)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	if isPackageManagedPath(self) {
+		return probe.NewError(fmt.Errorf("refusing to update %s, it is managed by your package manager", self))
+	}
+
+	downloadURL, ok := manifest.DownloadURL[platformKey()]
+	if !ok {
+		return probe.NewError(fmt.Errorf("no release published for %s", platformKey()))
+	}
+	digest, ok := manifest.Digest[platformKey()]
+	if !ok {
+		return probe.NewError(fmt.Errorf("manifest has no digest for %s, refusing to install an unverified binary", platformKey()))
+	}
+
+	tmpPath := self + ".update"
+	if err := downloadToFile(downloadURL, tmpPath, digest); err != nil {
+		return err
+	}
+	if e = os.Chmod(tmpPath, 0755); e != nil {
+		return probe.NewError(e)
+	}
+
+	if e = os.Rename(self, self+".bak"); e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.Rename(tmpPath, self); e != nil {
+		// Best effort restore of the running binary.
+		os.Rename(self+".bak", self)
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// rollbackUpdate restores the binary mc saved off before its last update.
+func rollbackUpdate() *probe.Error {
+	self, e := os.Executable()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	self, e = filepath.EvalSymlinks(self)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	backup := self + ".bak"
+	if _, e = os.Stat(backup); e != nil {
+		return probe.NewError(fmt.Errorf("no rollback binary found at %s", backup))
+	}
+	if e = os.Rename(backup, self); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// downloadToFile reads downloadURL fully into memory and writes it to
+// dstPath, which applyUpdate places beside the running binary so the final
+// os.Rename is atomic. It refuses to write the file out if its sha256
+// doesn't match the digest the manifest's signature
+// vouched for — this is what stops a compromised/MITM'd download host from
+// substituting a binary the signature check alone wouldn't catch, since the
+// signature covers the manifest's metadata, not the downloaded bytes.
+func downloadToFile(downloadURL, dstPath, expectedDigest string) *probe.Error {
+	resp, e := newHTTPClient().Get(downloadURL)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return probe.NewError(fmt.Errorf("unexpected download status: %s", resp.Status))
+	}
+
+	data, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return probe.NewError(e)
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != expectedDigest {
+		return probe.NewError(fmt.Errorf("downloaded binary does not match the digest in the signed manifest, refusing to install"))
+	}
+
+	if e = ioutil.WriteFile(dstPath, data, 0644); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+func platformKey() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+// isPackageManagedPath reports whether path falls under a location owned by
+// an OS package manager, where mc must refuse to self-update. Comparison is
+// segment-aware so "/usr/bin2/mc" isn't mistaken for living under "/usr/bin".
+func isPackageManagedPath(path string) bool {
+	path = filepath.Clean(path)
+	for _, p := range packageManagerPaths {
+		p = filepath.Clean(p)
+		if path == p || strings.HasPrefix(path, p+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
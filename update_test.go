@@ -0,0 +1,117 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func TestIsNewerRelease(t *testing.T) {
+	origVersion := Version
+	defer func() { Version = origVersion }()
+
+	Version = "2015-01-01T00:00:00Z"
+	newer, err := isNewerRelease("2016-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !newer {
+		t.Error("expected 2016 tag to be newer than 2015 Version")
+	}
+
+	newer, err = isNewerRelease("2014-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if newer {
+		t.Error("expected 2014 tag to not be newer than 2015 Version")
+	}
+}
+
+func TestVerifyManifestSignature(t *testing.T) {
+	origPubKey := updatePubKey
+	defer func() { updatePubKey = origPubKey }()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updatePubKey = base64.StdEncoding.EncodeToString(pub)
+
+	manifest := releaseManifest{
+		Tag:         "2016-01-01T00:00:00Z",
+		Channel:     "stable",
+		DownloadURL: map[string]string{"linux-amd64": "https://dl.minio.io/mc"},
+		Digest:      map[string]string{"linux-amd64": "deadbeef"},
+	}
+	payload, e := json.Marshal(manifest)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	manifest.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload))
+
+	if err := verifyManifestSignature(manifest); err != nil {
+		t.Errorf("expected valid signature to verify, got: %v", err)
+	}
+
+	manifest.Tag = "2099-01-01T00:00:00Z"
+	if err := verifyManifestSignature(manifest); err == nil {
+		t.Error("expected tampered manifest to fail signature verification")
+	}
+}
+
+func TestManifestURLForChannel(t *testing.T) {
+	cases := []struct {
+		base, channel, want string
+	}{
+		{"https://dl.minio.io/client/mc/release/manifest.json", "stable", "https://dl.minio.io/client/mc/release/manifest.json"},
+		{"https://dl.minio.io/client/mc/release/manifest.json", "", "https://dl.minio.io/client/mc/release/manifest.json"},
+		{"https://dl.minio.io/client/mc/release/manifest.json", "rc", "https://dl.minio.io/client/mc/release/manifest-rc.json"},
+	}
+	for _, c := range cases {
+		if got := manifestURLForChannel(c.base, c.channel); got != c.want {
+			t.Errorf("manifestURLForChannel(%q, %q) = %q, want %q", c.base, c.channel, got, c.want)
+		}
+	}
+}
+
+func TestPlatformKey(t *testing.T) {
+	if platformKey() == "" {
+		t.Error("expected platformKey() to return a non-empty GOOS-GOARCH pair")
+	}
+}
+
+func TestIsPackageManagedPath(t *testing.T) {
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{"/usr/bin/mc", true},
+		{"/opt/homebrew/bin/mc", true},
+		{"/home/user/bin/mc", false},
+		{"/usr/bin2/mc", false},
+		{"/usr/binfoo/mc", false},
+	}
+	for _, c := range cases {
+		if got := isPackageManagedPath(c.path); got != c.want {
+			t.Errorf("isPackageManagedPath(%q) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Minio Client (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMakeUserAgent(t *testing.T) {
+	ua := makeUserAgent("")
+	if !strings.HasPrefix(ua, "mc/") {
+		t.Errorf("expected User-Agent to start with %q, got %q", "mc/", ua)
+	}
+
+	ua = makeUserAgent("jenkins-worker-3")
+	if !strings.HasSuffix(ua, "jenkins-worker-3") {
+		t.Errorf("expected User-Agent to end with the extra token, got %q", ua)
+	}
+}
+
+func TestSetRequestHeaders(t *testing.T) {
+	globalUserAgent = "mc/test"
+	globalRequestID = "mc-test-id"
+
+	req, e := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+	setRequestHeaders(req)
+
+	if got := req.Header.Get("User-Agent"); got != globalUserAgent {
+		t.Errorf("User-Agent header = %q, want %q", got, globalUserAgent)
+	}
+	if got := req.Header.Get("X-Amz-Request-ID"); got != globalRequestID {
+		t.Errorf("X-Amz-Request-ID header = %q, want %q", got, globalRequestID)
+	}
+}
+
+// stubRoundTripper records the request it was handed and returns an error,
+// just enough to exercise headerTransport without making a real request.
+type stubRoundTripper struct {
+	got *http.Request
+}
+
+func (s *stubRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.got = req
+	return nil, errors.New("stub transport")
+}
+
+func TestHeaderTransportDoesNotMutateOriginalRequest(t *testing.T) {
+	globalUserAgent = "mc/test"
+	globalRequestID = "mc-test-id"
+
+	req, e := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if e != nil {
+		t.Fatalf("unexpected error: %v", e)
+	}
+
+	stub := &stubRoundTripper{}
+	transport := headerTransport{wrapped: stub}
+	transport.RoundTrip(req)
+
+	if got := req.Header.Get("User-Agent"); got != "" {
+		t.Errorf("caller's request was mutated: User-Agent = %q, want empty", got)
+	}
+	if got := stub.got.Header.Get("User-Agent"); got != globalUserAgent {
+		t.Errorf("wrapped transport got User-Agent = %q, want %q", got, globalUserAgent)
+	}
+	if stub.got == req {
+		t.Error("expected RoundTrip to pass a cloned request, not the original, to the wrapped transport")
+	}
+}